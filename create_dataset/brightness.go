@@ -5,15 +5,34 @@ import (
 	"math/rand"
 	"sort"
 
+	"github.com/bonedaddy/polish/create_dataset/postproc"
 	"github.com/unixpickle/model3d/render3d"
 )
 
+// RandomPostChain samples a randomized bloom/tone-map chain for a scene.
+func RandomPostChain() *postproc.Chain {
+	return postproc.RandomChain(rand.New(rand.NewSource(rand.Int63())))
+}
+
 func BrightnessScale(img *render3d.Image) float64 {
 	target := math.Min(0.9, math.Max(0.1, rand.NormFloat64()*0.1+0.3))
 	median := math.Max(1e-5, quantileBrightness(img))
 	return math.Max(1.0, target/median)
 }
 
+// ToLDR scales img to a randomized target brightness and runs it
+// through chain. Pass the same chain to both the "clean" and "noisy"
+// renders of a scene. The brightness scale is sampled anew on every
+// call, from img's own brightness; only chain is shared between calls.
+func ToLDR(img *render3d.Image, chain *postproc.Chain) *render3d.Image {
+	scale := BrightnessScale(img)
+	scaled := render3d.NewImage(img.Width, img.Height)
+	for i, c := range img.Data {
+		scaled.Data[i] = c.Scale(scale)
+	}
+	return chain.Apply(scaled)
+}
+
 func quantileBrightness(img *render3d.Image) float64 {
 	bs := make([]float64, len(img.Data))
 	for i, c := range img.Data {
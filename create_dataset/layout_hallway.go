@@ -0,0 +1,129 @@
+package main
+
+import (
+	"math/rand"
+
+	"github.com/unixpickle/model3d/model3d"
+	"github.com/unixpickle/model3d/render3d"
+)
+
+// wallThickness is the thin extent given to backdrop meshes along
+// their normal axis, so that they render as flat walls/floors/
+// ceilings rather than zero-volume planes.
+const wallThickness = 0.02
+
+// HallwayLayout is a long, narrow corridor with doorways cut into its
+// side walls and lights sparsely embedded in the ceiling. Compared to
+// RoomLayout, it produces strong perspective depth cues and raking
+// light along the corridor's side walls.
+type HallwayLayout struct {
+	Width     float64
+	Length    float64
+	NumDoors  int
+	DoorWidth float64
+}
+
+// doorHeight is how tall each doorway opening is; above it, the wall
+// continues up to the ceiling as a lintel.
+const doorHeight = 0.8
+
+func (h HallwayLayout) CameraInfo() (position, target model3d.Coord3D) {
+	return model3d.Coord3D{Z: 0.5, Y: -h.Length/2 + 1e-5}, model3d.Coord3D{Z: 0.5, Y: h.Length / 2}
+}
+
+func (h HallwayLayout) CreateLight() render3d.Object {
+	y := (rand.Float64() - 0.5) * h.Length
+	x := (rand.Float64() - 0.5) * h.Width * 0.6
+	center := model3d.Coord3D{X: x, Y: y, Z: 1 - wallThickness}
+
+	shape := &model3d.Rect{
+		MinVal: center.Sub(model3d.Coord3D{X: 0.1, Y: 0.1, Z: 0.02}),
+		MaxVal: center.Add(model3d.Coord3D{X: 0.1, Y: 0.1, Z: wallThickness}),
+	}
+	return &render3d.ColliderObject{
+		Collider: shape,
+		Material: &render3d.LambertMaterial{
+			EmissionColor: render3d.NewColor((rand.Float64() + 0.1) * 10),
+		},
+	}
+}
+
+func (h HallwayLayout) CreateBackdrop() []*model3d.Mesh {
+	var walls []*model3d.Mesh
+
+	walls = append(walls, thinBox(
+		model3d.Coord3D{X: -h.Width / 2, Y: -h.Length / 2, Z: 1 - wallThickness},
+		model3d.Coord3D{X: h.Width / 2, Y: h.Length / 2, Z: 1},
+	))
+	walls = append(walls, thinBox(
+		model3d.Coord3D{X: -h.Width / 2, Y: -h.Length / 2, Z: 0},
+		model3d.Coord3D{X: h.Width / 2, Y: h.Length / 2, Z: wallThickness},
+	))
+
+	// Cap both ends of the corridor so that rays which miss every
+	// door and light have somewhere to terminate instead of escaping
+	// into open space. The caps sit just outside [-Length/2, Length/2]
+	// so they don't overlap the epsilon-offset camera in CameraInfo.
+	walls = append(walls, thinBox(
+		model3d.Coord3D{X: -h.Width / 2, Y: -h.Length/2 - wallThickness, Z: 0},
+		model3d.Coord3D{X: h.Width / 2, Y: -h.Length / 2, Z: 1},
+	))
+	walls = append(walls, thinBox(
+		model3d.Coord3D{X: -h.Width / 2, Y: h.Length / 2, Z: 0},
+		model3d.Coord3D{X: h.Width / 2, Y: h.Length/2 + wallThickness, Z: 1},
+	))
+
+	doorYs := h.doorCenters()
+	for _, x := range []float64{-h.Width / 2, h.Width / 2} {
+		for _, seg := range wallSegments(-h.Length/2, h.Length/2, doorYs, h.DoorWidth) {
+			walls = append(walls, thinBox(
+				model3d.Coord3D{X: x - wallThickness, Y: seg[0], Z: 0},
+				model3d.Coord3D{X: x + wallThickness, Y: seg[1], Z: 1},
+			))
+		}
+		for _, dy := range doorYs {
+			walls = append(walls, thinBox(
+				model3d.Coord3D{X: x - wallThickness, Y: dy - h.DoorWidth/2, Z: doorHeight},
+				model3d.Coord3D{X: x + wallThickness, Y: dy + h.DoorWidth/2, Z: 1},
+			))
+		}
+	}
+
+	return walls
+}
+
+func (h HallwayLayout) PlaceMesh(m *model3d.Mesh) *model3d.Mesh {
+	placeMin := model3d.Coord3D{X: -h.Width / 2}
+	placeMax := model3d.Coord3D{X: h.Width / 2, Y: h.Length / 2, Z: 1}
+	return placeInBounds(placeMin, placeMax, m)
+}
+
+// doorCenters evenly spaces h.NumDoors doorways along the corridor,
+// leaving room on either end.
+func (h HallwayLayout) doorCenters() []float64 {
+	centers := make([]float64, h.NumDoors)
+	spacing := h.Length / float64(h.NumDoors+1)
+	for i := range centers {
+		centers[i] = -h.Length/2 + spacing*float64(i+1)
+	}
+	return centers
+}
+
+// wallSegments splits [min, max] into the solid spans of wall that
+// remain once a doorWidth-wide gap is cut out around each of doorYs.
+func wallSegments(min, max float64, doorYs []float64, doorWidth float64) [][2]float64 {
+	var segs [][2]float64
+	cur := min
+	for _, dy := range doorYs {
+		segs = append(segs, [2]float64{cur, dy - doorWidth/2})
+		cur = dy + doorWidth/2
+	}
+	segs = append(segs, [2]float64{cur, max})
+	return segs
+}
+
+// thinBox creates a flat, thin box mesh spanning min to max, used to
+// represent a single wall/floor/ceiling panel.
+func thinBox(min, max model3d.Coord3D) *model3d.Mesh {
+	return model3d.NewMeshRect(min, max)
+}
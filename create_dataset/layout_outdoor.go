@@ -0,0 +1,78 @@
+package main
+
+import (
+	"math/rand"
+
+	"github.com/unixpickle/model3d/model3d"
+	"github.com/unixpickle/model3d/render3d"
+)
+
+// OutdoorLayout is an open exterior scene: a ground plane lit by a
+// distant, bright "sun" and a dim, wide sky dome, so the network
+// learns to denoise scenes with a dominant directional light source
+// instead of the enclosed, wall-bounded lighting of RoomLayout and
+// HallwayLayout.
+type OutdoorLayout struct {
+	GroundRadius float64
+	SunHeight    float64
+}
+
+func (o OutdoorLayout) CameraInfo() (position, target model3d.Coord3D) {
+	return model3d.Coord3D{Z: 1.7, Y: -o.GroundRadius / 4}, model3d.Coord3D{Z: 1.0, Y: o.GroundRadius / 4}
+}
+
+func (o OutdoorLayout) CreateLight() render3d.Object {
+	if rand.Intn(3) == 0 {
+		return o.sunLight()
+	}
+	return o.skyLight()
+}
+
+// sunLight creates a small, very bright sphere high above the ground,
+// standing in for a directional sun.
+func (o OutdoorLayout) sunLight() render3d.Object {
+	center := model3d.Coord3D{
+		X: (rand.Float64() - 0.5) * o.GroundRadius,
+		Y: (rand.Float64() - 0.5) * o.GroundRadius,
+		Z: o.GroundRadius * o.SunHeight,
+	}
+	shape := &model3d.Sphere{Center: center, Radius: o.GroundRadius * 0.04}
+	return &render3d.ColliderObject{
+		Collider: shape,
+		Material: &render3d.LambertMaterial{
+			EmissionColor: render3d.NewColor((rand.Float64() + 0.5) * 50),
+		},
+	}
+}
+
+// skyLight creates a large, dim sphere far from the scene, standing
+// in for the ambient light of a wide sky hemisphere.
+func (o OutdoorLayout) skyLight() render3d.Object {
+	center := model3d.Coord3D{
+		X: (rand.Float64() - 0.5) * o.GroundRadius * 2,
+		Y: (rand.Float64() - 0.5) * o.GroundRadius * 2,
+		Z: o.GroundRadius * (o.SunHeight + 0.5),
+	}
+	shape := &model3d.Sphere{Center: center, Radius: o.GroundRadius * 0.5}
+	return &render3d.ColliderObject{
+		Collider: shape,
+		Material: &render3d.LambertMaterial{
+			EmissionColor: render3d.NewColor(rand.Float64()*0.5 + 0.5),
+		},
+	}
+}
+
+func (o OutdoorLayout) CreateBackdrop() []*model3d.Mesh {
+	return []*model3d.Mesh{
+		thinBox(
+			model3d.Coord3D{X: -o.GroundRadius, Y: -o.GroundRadius, Z: -wallThickness},
+			model3d.Coord3D{X: o.GroundRadius, Y: o.GroundRadius, Z: 0},
+		),
+	}
+}
+
+func (o OutdoorLayout) PlaceMesh(m *model3d.Mesh) *model3d.Mesh {
+	placeMin := model3d.Coord3D{X: -o.GroundRadius / 4, Y: -o.GroundRadius / 4}
+	placeMax := model3d.Coord3D{X: o.GroundRadius / 4, Y: o.GroundRadius / 4, Z: 2.0}
+	return placeInBounds(placeMin, placeMax, m)
+}
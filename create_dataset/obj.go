@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// ReadOBJ reads a Wavefront OBJ file and triangulates its faces,
+// fan-triangulating any n-gons.
+//
+// It only looks at "v" and "f" directives; normals (vn), texture
+// coordinates (vt), groups (g/s), and material references (usemtl)
+// are ignored, since the resulting triangles feed directly into
+// model3d.NewMeshTriangles.
+func ReadOBJ(r io.Reader) ([]*model3d.Triangle, error) {
+	var verts []model3d.Coord3D
+	var tris []*model3d.Triangle
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "v":
+			coord, err := parseOBJVertex(fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("read obj: line %d: %s", lineNum, err)
+			}
+			verts = append(verts, coord)
+		case "f":
+			faceTris, err := parseOBJFace(verts, fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("read obj: line %d: %s", lineNum, err)
+			}
+			tris = append(tris, faceTris...)
+		default:
+			// Skip unsupported directives (vn, vt, g, s, usemtl, mtllib, o, ...).
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read obj: %s", err)
+	}
+	return tris, nil
+}
+
+func parseOBJVertex(fields []string) (model3d.Coord3D, error) {
+	if len(fields) < 3 {
+		return model3d.Coord3D{}, fmt.Errorf("expected 3 coordinates for vertex, got %d", len(fields))
+	}
+	coords := make([]float64, 3)
+	for i := 0; i < 3; i++ {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return model3d.Coord3D{}, fmt.Errorf("invalid vertex coordinate: %s", fields[i])
+		}
+		coords[i] = v
+	}
+	return model3d.Coord3D{X: coords[0], Y: coords[1], Z: coords[2]}, nil
+}
+
+func parseOBJFace(verts []model3d.Coord3D, fields []string) ([]*model3d.Triangle, error) {
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("expected at least 3 vertices for face, got %d", len(fields))
+	}
+	indices := make([]int, len(fields))
+	for i, f := range fields {
+		idxStr := strings.SplitN(f, "/", 2)[0]
+		idx, err := strconv.Atoi(idxStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid face vertex index: %s", f)
+		}
+		if idx < 0 {
+			idx = len(verts) + idx + 1
+		}
+		if idx < 1 || idx > len(verts) {
+			return nil, fmt.Errorf("face vertex index %d out of range (have %d vertices)", idx, len(verts))
+		}
+		indices[i] = idx - 1
+	}
+
+	// Fan-triangulate n-gons around the first vertex.
+	var tris []*model3d.Triangle
+	for i := 1; i < len(indices)-1; i++ {
+		tris = append(tris, &model3d.Triangle{
+			verts[indices[0]],
+			verts[indices[i]],
+			verts[indices[i+1]],
+		})
+	}
+	return tris, nil
+}
+
+// loadTriangles reads the triangles of a 3D model file, dispatching
+// on the file extension so that RandomScene can mix OFF, OBJ, and STL
+// models in the same pool without a manual conversion step.
+func loadTriangles(path string) ([]*model3d.Triangle, error) {
+	r, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".off":
+		return model3d.ReadOFF(r)
+	case ".obj":
+		return ReadOBJ(r)
+	case ".stl":
+		return model3d.ReadSTL(r)
+	default:
+		return nil, fmt.Errorf("load triangles: unsupported model extension: %s", path)
+	}
+}
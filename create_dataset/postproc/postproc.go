@@ -0,0 +1,127 @@
+// Package postproc applies the same bloom and tone-mapping that real
+// rendered images pick up before display, so that training a denoiser
+// on linearly-scaled HDR images doesn't teach it the wrong prior.
+package postproc
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/unixpickle/model3d/render3d"
+)
+
+// A Chain applies bloom followed by Reinhard tone mapping and an
+// optional gamma curve.
+type Chain struct {
+	BloomThreshold float64
+	BloomRadius    int
+	BloomStrength  float64
+	Gamma          float64
+}
+
+// RandomChain samples a randomized bloom/tone-map configuration, so
+// that training scenes see a variety of post-processing strength.
+func RandomChain(rng *rand.Rand) *Chain {
+	return &Chain{
+		BloomThreshold: rng.Float64()*0.5 + 0.5,
+		BloomRadius:    rng.Intn(8) + 2,
+		BloomStrength:  rng.Float64()*0.5 + 0.1,
+		Gamma:          2.2,
+	}
+}
+
+// Apply runs bloom and tone mapping on img, returning a new image.
+func (c *Chain) Apply(img *render3d.Image) *render3d.Image {
+	bloomed := Bloom(img, c.BloomThreshold, c.BloomRadius, c.BloomStrength)
+	mapped := Reinhard(bloomed)
+	if c.Gamma != 0 {
+		mapped = ApplyGamma(mapped, c.Gamma)
+	}
+	return mapped
+}
+
+// Bloom thresholds pixels above T, blurs them with a box blur of the
+// given radius (three passes, approximating a Gaussian), and adds the
+// blurred result back to img scaled by strength.
+func Bloom(img *render3d.Image, t float64, radius int, strength float64) *render3d.Image {
+	bright := render3d.NewImage(img.Width, img.Height)
+	for i, c := range img.Data {
+		brightness := c.Sum() / 3.0
+		if brightness > t {
+			bright.Data[i] = c
+		}
+	}
+
+	blurred := bright
+	for i := 0; i < 3; i++ {
+		blurred = boxBlur(blurred, radius)
+	}
+
+	out := render3d.NewImage(img.Width, img.Height)
+	for i, c := range img.Data {
+		out.Data[i] = c.Add(blurred.Data[i].Scale(strength))
+	}
+	return out
+}
+
+// boxBlur applies a single (2*radius+1) x (2*radius+1) box average to
+// img, separated into a horizontal and vertical pass.
+func boxBlur(img *render3d.Image, radius int) *render3d.Image {
+	return boxBlur1D(boxBlur1D(img, radius, true), radius, false)
+}
+
+// boxBlur1D averages each pixel of img with its radius neighbors
+// along a single axis: along x when horizontal is true, along y
+// otherwise.
+func boxBlur1D(img *render3d.Image, radius int, horizontal bool) *render3d.Image {
+	out := render3d.NewImage(img.Width, img.Height)
+	for y := 0; y < img.Height; y++ {
+		for x := 0; x < img.Width; x++ {
+			var sum render3d.Color
+			var count float64
+			for d := -radius; d <= radius; d++ {
+				nx, ny := x, y
+				if horizontal {
+					nx = x + d
+				} else {
+					ny = y + d
+				}
+				if nx < 0 || nx >= img.Width || ny < 0 || ny >= img.Height {
+					continue
+				}
+				sum = sum.Add(img.Data[ny*img.Width+nx])
+				count++
+			}
+			out.Data[y*img.Width+x] = sum.Scale(1 / count)
+		}
+	}
+	return out
+}
+
+// Reinhard applies the Reinhard tone map, c' = c / (1 + c), to every
+// channel of img.
+func Reinhard(img *render3d.Image) *render3d.Image {
+	out := render3d.NewImage(img.Width, img.Height)
+	for i, c := range img.Data {
+		out.Data[i] = render3d.Color{
+			X: c.X / (1 + c.X),
+			Y: c.Y / (1 + c.Y),
+			Z: c.Z / (1 + c.Z),
+		}
+	}
+	return out
+}
+
+// ApplyGamma raises every channel of img to the power 1/gamma.
+func ApplyGamma(img *render3d.Image, gamma float64) *render3d.Image {
+	out := render3d.NewImage(img.Width, img.Height)
+	invGamma := 1 / gamma
+	for i, c := range img.Data {
+		out.Data[i] = render3d.Color{
+			X: math.Pow(math.Max(c.X, 0), invGamma),
+			Y: math.Pow(math.Max(c.Y, 0), invGamma),
+			Z: math.Pow(math.Max(c.Z, 0), invGamma),
+		}
+	}
+	return out
+}
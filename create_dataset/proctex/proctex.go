@@ -0,0 +1,137 @@
+// Package proctex generates procedural albedo textures, so that the
+// polish data-generation pipeline can diversify (or entirely replace)
+// its image corpus without depending on external assets.
+package proctex
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/unixpickle/model3d/render3d"
+)
+
+// Size is the width and height, in pixels, of every texture produced
+// by this package.
+const Size = 256
+
+// ProceduralTexture samples a random procedural texture kind and
+// renders it to a Size x Size image.
+func ProceduralTexture(rng *rand.Rand) *render3d.Image {
+	switch rng.Intn(4) {
+	case 0:
+		return Checkerboard(rng)
+	case 1:
+		return Gradient(rng)
+	case 2:
+		return Noise(rng)
+	default:
+		return Stripes(rng)
+	}
+}
+
+// Checkerboard renders a checkerboard of two random colors with a
+// randomized cell size.
+func Checkerboard(rng *rand.Rand) *render3d.Image {
+	c1, c2 := randomColor(rng), randomColor(rng)
+	cellSize := rng.Intn(32) + 4
+	img := render3d.NewImage(Size, Size)
+	for y := 0; y < Size; y++ {
+		for x := 0; x < Size; x++ {
+			if ((x/cellSize)+(y/cellSize))%2 == 0 {
+				img.Data[y*Size+x] = c1
+			} else {
+				img.Data[y*Size+x] = c2
+			}
+		}
+	}
+	return img
+}
+
+// Gradient renders a linear or radial gradient between two random
+// colors.
+func Gradient(rng *rand.Rand) *render3d.Image {
+	c1, c2 := randomColor(rng), randomColor(rng)
+	img := render3d.NewImage(Size, Size)
+	radial := rng.Intn(2) == 0
+	for y := 0; y < Size; y++ {
+		for x := 0; x < Size; x++ {
+			var t float64
+			if radial {
+				dx := float64(x)/Size - 0.5
+				dy := float64(y)/Size - 0.5
+				t = math.Min(1, math.Sqrt(dx*dx+dy*dy)/0.5)
+			} else {
+				t = float64(y) / Size
+			}
+			img.Data[y*Size+x] = lerpColor(c1, c2, t)
+		}
+	}
+	return img
+}
+
+// Noise renders a value-noise field: a coarse grid of random
+// brightness values, smoothly interpolated up to Size x Size.
+func Noise(rng *rand.Rand) *render3d.Image {
+	gridSize := rng.Intn(6) + 3
+	c1, c2 := randomColor(rng), randomColor(rng)
+	grid := make([][]float64, gridSize+1)
+	for i := range grid {
+		grid[i] = make([]float64, gridSize+1)
+		for j := range grid[i] {
+			grid[i][j] = rng.Float64()
+		}
+	}
+
+	img := render3d.NewImage(Size, Size)
+	for y := 0; y < Size; y++ {
+		for x := 0; x < Size; x++ {
+			fx := float64(x) / Size * float64(gridSize)
+			fy := float64(y) / Size * float64(gridSize)
+			ix, iy := int(fx), int(fy)
+			tx, ty := fx-float64(ix), fy-float64(iy)
+			v := bilerp(grid[iy][ix], grid[iy][ix+1], grid[iy+1][ix], grid[iy+1][ix+1], tx, ty)
+			img.Data[y*Size+x] = lerpColor(c1, c2, v)
+		}
+	}
+	return img
+}
+
+// Stripes renders alternating stripes of two random colors, at a
+// randomized angle and width.
+func Stripes(rng *rand.Rand) *render3d.Image {
+	c1, c2 := randomColor(rng), randomColor(rng)
+	width := float64(rng.Intn(24) + 4)
+	angle := rng.Float64() * math.Pi
+	dx, dy := math.Cos(angle), math.Sin(angle)
+
+	img := render3d.NewImage(Size, Size)
+	for y := 0; y < Size; y++ {
+		for x := 0; x < Size; x++ {
+			proj := float64(x)*dx + float64(y)*dy
+			if int(math.Floor(proj/width))%2 == 0 {
+				img.Data[y*Size+x] = c1
+			} else {
+				img.Data[y*Size+x] = c2
+			}
+		}
+	}
+	return img
+}
+
+func randomColor(rng *rand.Rand) render3d.Color {
+	return render3d.Color{X: rng.Float64(), Y: rng.Float64(), Z: rng.Float64()}
+}
+
+func lerpColor(c1, c2 render3d.Color, t float64) render3d.Color {
+	return render3d.Color{
+		X: c1.X + (c2.X-c1.X)*t,
+		Y: c1.Y + (c2.Y-c1.Y)*t,
+		Z: c1.Z + (c2.Z-c1.Z)*t,
+	}
+}
+
+func bilerp(v00, v10, v01, v11, tx, ty float64) float64 {
+	top := v00 + (v10-v00)*tx
+	bottom := v01 + (v11-v01)*tx
+	return top + (bottom-top)*ty
+}
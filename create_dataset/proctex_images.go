@@ -0,0 +1,46 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+
+	"github.com/bonedaddy/polish/create_dataset/proctex"
+)
+
+// numProceduralTextures is how many procedural textures are generated
+// as a stand-in image pool when RandomScene isn't given any images.
+const numProceduralTextures = 16
+
+// proceduralImagePaths writes n freshly generated procedural textures
+// to temporary PNG files and returns their paths, so they can be fed
+// into RandomizeMaterial's existing image pool without changing its
+// signature.
+//
+// The returned cleanup function removes all of the temporary files;
+// callers must call it once every RandomizeMaterial call that might
+// read the images has completed, so that a real corpus-generation run
+// doesn't leak a handful of files per scene into the OS temp dir.
+func proceduralImagePaths(n int) (paths []string, cleanup func(), err error) {
+	paths = make([]string, 0, n)
+	cleanup = func() {
+		for _, p := range paths {
+			os.Remove(p)
+		}
+	}
+	for i := 0; i < n; i++ {
+		img := proctex.ProceduralTexture(rand.New(rand.NewSource(rand.Int63())))
+		f, err := os.CreateTemp("", "proctex-*.png")
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		name := f.Name()
+		f.Close()
+		paths = append(paths, name)
+		if err := img.Save(name); err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+	}
+	return paths, cleanup, nil
+}
@@ -3,20 +3,35 @@ package main
 import (
 	"math"
 	"math/rand"
-	"os"
 
+	"github.com/bonedaddy/polish/create_dataset/postproc"
 	"github.com/unixpickle/essentials"
 	"github.com/unixpickle/model3d/model3d"
 	"github.com/unixpickle/model3d/render3d"
 )
 
-// RandomScene creates a random collection of objects and
-// fills out a renderer to render them.
-func RandomScene(models, images []string) (render3d.Object, *render3d.RecursiveRayTracer) {
+// RandomScene creates a random collection of objects and fills out a
+// renderer to render them, along with the post-processing chain that
+// should be applied to every image rendered from the scene (via
+// ToLDR), so the "clean" and "noisy" renders share one bloom and
+// tone-mapping chain.
+//
+// The models argument may mix .off, .obj, and .stl paths; the
+// extension of each path determines how it's parsed, so any public
+// model zoo that ships OBJ can be used without a manual conversion
+// step.
+func RandomScene(models, images []string) (render3d.Object, *render3d.RecursiveRayTracer, *postproc.Chain) {
 	layout := RandomSceneLayout()
 	numObjects := rand.Intn(10) + 1
 	numLights := rand.Intn(5) + 1
 
+	if len(images) == 0 {
+		procImages, cleanup, err := proceduralImagePaths(numProceduralTextures)
+		essentials.Must(err)
+		defer cleanup()
+		images = procImages
+	}
+
 	var objects render3d.JoinedObject
 	var focusPoints []render3d.FocusPoint
 	var focusProbs []float64
@@ -27,10 +42,7 @@ func RandomScene(models, images []string) (render3d.Object, *render3d.RecursiveR
 
 	for i := 0; i < numObjects; i++ {
 		path := models[rand.Intn(len(models))]
-		r, err := os.Open(path)
-		essentials.Must(err)
-		defer r.Close()
-		tris, err := model3d.ReadOFF(r)
+		tris, err := loadTriangles(path)
 		essentials.Must(err)
 		mesh := model3d.NewMeshTriangles(tris)
 		rotation := model3d.NewMatrix3Rotation(model3d.NewCoord3DRandUnit(),
@@ -57,18 +69,58 @@ func RandomScene(models, images []string) (render3d.Object, *render3d.RecursiveR
 		Camera:          render3d.NewCameraAt(origin, target, fov),
 		FocusPoints:     focusPoints,
 		FocusPointProbs: focusProbs,
-	}
+	}, RandomPostChain()
 }
 
-// RandomSceneLayout samples a SceneLayout from some
-// distribution.
+// RandomSceneLayout samples a SceneLayout uniformly from the
+// registered layouts.
 func RandomSceneLayout() SceneLayout {
-	return RoomLayout{
-		Width: rand.Float64()*2.0 + 0.5,
-		Depth: rand.Float64()*20.0 + 5.0,
+	factories := make([]func() SceneLayout, 0, len(layoutRegistry))
+	for _, factory := range layoutRegistry {
+		factories = append(factories, factory)
 	}
+	return factories[rand.Intn(len(factories))]()
+}
+
+// layoutRegistry maps a layout's name to a factory that creates a
+// randomized instance of it. It's populated by RegisterLayout, with
+// the built-in layouts registering themselves in init().
+var layoutRegistry = map[string]func() SceneLayout{}
+
+// RegisterLayout adds a named SceneLayout factory to the set that
+// RandomSceneLayout samples from. Registering a name that's already
+// in use overwrites the existing factory.
+func RegisterLayout(name string, factory func() SceneLayout) {
+	layoutRegistry[name] = factory
+}
+
+func init() {
+	RegisterLayout("room", func() SceneLayout {
+		return RoomLayout{
+			Width: rand.Float64()*2.0 + 0.5,
+			Depth: rand.Float64()*20.0 + 5.0,
+		}
+	})
+	RegisterLayout("hallway", func() SceneLayout {
+		return HallwayLayout{
+			Width:     rand.Float64()*1.0 + 1.0,
+			Length:    rand.Float64()*30.0 + 10.0,
+			NumDoors:  rand.Intn(4) + 1,
+			DoorWidth: rand.Float64()*0.4 + 0.6,
+		}
+	})
+	RegisterLayout("outdoor", func() SceneLayout {
+		return OutdoorLayout{
+			GroundRadius: rand.Float64()*30.0 + 10.0,
+			SunHeight:    rand.Float64()*0.5 + 0.5,
+		}
+	})
 }
 
+// SceneLayout describes the topology of a rendered scene: where the
+// camera sits, what the walls/floor look like, and how lights and
+// objects are placed within it. Use RegisterLayout to add more
+// layouts to the pool that RandomSceneLayout samples from.
 type SceneLayout interface {
 	// CameraInfo determines where the scene would like to
 	// setup the camera for rendering.
@@ -0,0 +1,206 @@
+package nn
+
+import "math"
+
+// A LeakyReLU layer applies the leaky rectified linear unit, passing
+// negative inputs through scaled by Slope instead of zeroing them.
+type LeakyReLU struct {
+	Slope float64
+}
+
+// Apply applies the leaky rectified linear unit.
+func (l LeakyReLU) Apply(t *Tensor) *Tensor {
+	res := NewTensor(t.Height, t.Width, t.Depth)
+	for i, x := range t.Data {
+		if x > 0 {
+			res.Data[i] = x
+		} else {
+			res.Data[i] = x * l.Slope
+		}
+	}
+	return res
+}
+
+// A Sigmoid layer applies the logistic sigmoid function.
+type Sigmoid struct{}
+
+// Apply applies the logistic sigmoid function.
+func (s Sigmoid) Apply(t *Tensor) *Tensor {
+	res := NewTensor(t.Height, t.Width, t.Depth)
+	for i, x := range t.Data {
+		res.Data[i] = 1 / (1 + math.Exp(-x))
+	}
+	return res
+}
+
+// A Tanh layer applies the hyperbolic tangent function.
+type Tanh struct{}
+
+// Apply applies the hyperbolic tangent function.
+func (tn Tanh) Apply(t *Tensor) *Tensor {
+	res := NewTensor(t.Height, t.Width, t.Depth)
+	for i, x := range t.Data {
+		res.Data[i] = math.Tanh(x)
+	}
+	return res
+}
+
+// A BatchNorm layer applies a per-channel affine normalization using
+// fixed statistics, as produced by training-time batch normalization.
+type BatchNorm struct {
+	Scale []float64
+	Bias  []float64
+	Mean  []float64
+	Var   []float64
+	Eps   float64
+}
+
+// Apply normalizes and affine-transforms each channel of t.
+func (b *BatchNorm) Apply(t *Tensor) *Tensor {
+	res := NewTensor(t.Height, t.Width, t.Depth)
+	for y := 0; y < t.Height; y++ {
+		for x := 0; x < t.Width; x++ {
+			for c := 0; c < t.Depth; c++ {
+				i := (y*t.Width+x)*t.Depth + c
+				normalized := (t.Data[i] - b.Mean[c]) / math.Sqrt(b.Var[c]+b.Eps)
+				res.Data[i] = normalized*b.Scale[c] + b.Bias[c]
+			}
+		}
+	}
+	return res
+}
+
+// A GroupNorm layer splits channels into NumGroups groups and
+// normalizes each group using statistics computed over that group's
+// channels and spatial extent, making it suitable at inference with a
+// batch size of one (unlike BatchNorm, which needs stored running
+// statistics).
+type GroupNorm struct {
+	NumGroups int
+	Scale     []float64
+	Bias      []float64
+	Eps       float64
+}
+
+// Apply normalizes and affine-transforms each group of t.
+func (g *GroupNorm) Apply(t *Tensor) *Tensor {
+	res := NewTensor(t.Height, t.Width, t.Depth)
+	channelsPerGroup := t.Depth / g.NumGroups
+	for group := 0; group < g.NumGroups; group++ {
+		cStart := group * channelsPerGroup
+		cEnd := cStart + channelsPerGroup
+
+		var sum, sqSum float64
+		var count float64
+		for y := 0; y < t.Height; y++ {
+			for x := 0; x < t.Width; x++ {
+				for c := cStart; c < cEnd; c++ {
+					v := t.Data[(y*t.Width+x)*t.Depth+c]
+					sum += v
+					sqSum += v * v
+					count++
+				}
+			}
+		}
+		mean := sum / count
+		variance := sqSum/count - mean*mean
+
+		for y := 0; y < t.Height; y++ {
+			for x := 0; x < t.Width; x++ {
+				for c := cStart; c < cEnd; c++ {
+					i := (y*t.Width+x)*t.Depth + c
+					normalized := (t.Data[i] - mean) / math.Sqrt(variance+g.Eps)
+					res.Data[i] = normalized*g.Scale[c] + g.Bias[c]
+				}
+			}
+		}
+	}
+	return res
+}
+
+// A Layer is anything that transforms one Tensor into another. Concat
+// and Residual use it to hold their sub-networks.
+type Layer interface {
+	Apply(t *Tensor) *Tensor
+}
+
+// A Concat layer runs each of Layers on the input and concatenates
+// their outputs along the channel axis, for use in U-Net-style skip
+// connections.
+type Concat struct {
+	Layers []Layer
+}
+
+// Apply runs every sub-layer on t and concatenates the results.
+func (c *Concat) Apply(t *Tensor) *Tensor {
+	outputs := make([]*Tensor, len(c.Layers))
+	depth := 0
+	for i, l := range c.Layers {
+		outputs[i] = l.Apply(t)
+		depth += outputs[i].Depth
+	}
+
+	res := NewTensor(t.Height, t.Width, depth)
+	for y := 0; y < t.Height; y++ {
+		for x := 0; x < t.Width; x++ {
+			outC := 0
+			for _, out := range outputs {
+				for c := 0; c < out.Depth; c++ {
+					res.Data[(y*t.Width+x)*depth+outC] = out.Data[(y*out.Width+x)*out.Depth+c]
+					outC++
+				}
+			}
+		}
+	}
+	return res
+}
+
+// A Residual layer runs its sub-network on the input and adds the
+// input back to the result.
+type Residual struct {
+	Layers []Layer
+}
+
+// Apply runs the sub-network on t and adds t to the result.
+func (r *Residual) Apply(t *Tensor) *Tensor {
+	out := t
+	for _, l := range r.Layers {
+		out = l.Apply(out)
+	}
+
+	res := NewTensor(out.Height, out.Width, out.Depth)
+	for i, x := range out.Data {
+		res.Data[i] = x + t.Data[i]
+	}
+	return res
+}
+
+// A PixelShuffle layer rearranges BlockSize*BlockSize groups of
+// channels into spatial resolution, upsampling an image without the
+// checkerboard artifacts of transposed convolution.
+type PixelShuffle struct {
+	BlockSize int
+}
+
+// Apply rearranges t from (H, W, C*r^2) to (H*r, W*r, C), where
+// r = p.BlockSize.
+func (p *PixelShuffle) Apply(t *Tensor) *Tensor {
+	r := p.BlockSize
+	outDepth := t.Depth / (r * r)
+	res := NewTensor(t.Height*r, t.Width*r, outDepth)
+
+	for y := 0; y < t.Height; y++ {
+		for x := 0; x < t.Width; x++ {
+			for c := 0; c < t.Depth; c++ {
+				outC := c / (r * r)
+				block := c % (r * r)
+				dy := block / r
+				dx := block % r
+				outY := y*r + dy
+				outX := x*r + dx
+				res.Data[(outY*res.Width+outX)*outDepth+outC] = t.Data[(y*t.Width+x)*t.Depth+c]
+			}
+		}
+	}
+	return res
+}
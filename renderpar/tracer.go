@@ -0,0 +1,153 @@
+// Package renderpar parallelizes render3d ray tracers across tiles of
+// the output image, so that generating the training corpus for polish
+// doesn't take orders of magnitude longer than it needs to.
+package renderpar
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/unixpickle/model3d/render3d"
+)
+
+// A ParallelTracer renders an image by splitting it into tiles and
+// farming each tile out to a pool of worker goroutines, each with its
+// own *rand.Rand so that Monte Carlo sampling stays reproducible and
+// never contends on math/rand's global source.
+type ParallelTracer struct {
+	Tracer *render3d.RecursiveRayTracer
+
+	// TileSize is the width and height, in pixels, of each tile. If
+	// zero, DefaultTileSize is used.
+	TileSize int
+
+	// NumWorkers is the size of the goroutine pool. If zero,
+	// runtime.NumCPU() is used.
+	NumWorkers int
+
+	// Seed sets the starting seed from which each tile's random
+	// samples are derived. If zero, workers are seeded
+	// non-deterministically.
+	Seed int64
+}
+
+// DefaultTileSize is used by ParallelTracer when TileSize is zero.
+const DefaultTileSize = 32
+
+// NewParallelTracer creates a ParallelTracer that wraps tracer with
+// default tiling and worker-pool settings.
+func NewParallelTracer(tracer *render3d.RecursiveRayTracer) *ParallelTracer {
+	return &ParallelTracer{Tracer: tracer}
+}
+
+// Render renders obj into img, tiling the image across a pool of
+// worker goroutines.
+//
+// If progress is non-nil, it is called after every completed tile
+// with the number of tiles completed so far and the total tile count.
+// If ctx is cancelled, Render stops dispatching new tiles and returns
+// ctx.Err() once in-flight tiles finish.
+func (p *ParallelTracer) Render(ctx context.Context, img *render3d.Image, obj render3d.Object,
+	progress func(done, total int)) error {
+	tileSize := p.TileSize
+	if tileSize == 0 {
+		tileSize = DefaultTileSize
+	}
+	numWorkers := p.NumWorkers
+	if numWorkers == 0 {
+		numWorkers = runtime.NumCPU()
+	}
+
+	tiles := tilesForImage(img.Width, img.Height, tileSize)
+
+	seed := p.Seed
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+	rands := make([]*rand.Rand, numWorkers)
+	for i := range rands {
+		rands[i] = rand.New(rand.NewSource(seed + int64(i)))
+	}
+
+	var (
+		mu       sync.Mutex
+		done     int
+		tileChan = make(chan tile)
+		wg       sync.WaitGroup
+	)
+
+	wg.Add(numWorkers)
+	for w := 0; w < numWorkers; w++ {
+		gen := rands[w]
+		go func() {
+			defer wg.Done()
+			for t := range tileChan {
+				if ctx.Err() != nil {
+					continue
+				}
+				// Reseed from the tile's position, so the samples of a
+				// tile don't depend on which worker picks it up.
+				gen.Seed(seed + int64(t.minY*img.Width+t.minX))
+				p.renderTile(gen, img, obj, t)
+				mu.Lock()
+				done++
+				if progress != nil {
+					progress(done, len(tiles))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, t := range tiles {
+		select {
+		case tileChan <- t:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(tileChan)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// renderTile renders a single tile of img using gen as the source of
+// randomness for every sample in the tile.
+func (p *ParallelTracer) renderTile(gen *rand.Rand, img *render3d.Image, obj render3d.Object, t tile) {
+	for y := t.minY; y < t.maxY; y++ {
+		for x := t.minX; x < t.maxX; x++ {
+			ray := p.Tracer.Camera.Ray(float64(x), float64(y), img.Width, img.Height)
+			img.Data[y*img.Width+x] = p.Tracer.RayColor(gen, ray, obj)
+		}
+	}
+}
+
+type tile struct {
+	minX, minY, maxX, maxY int
+}
+
+func tilesForImage(width, height, tileSize int) []tile {
+	var tiles []tile
+	for y := 0; y < height; y += tileSize {
+		for x := 0; x < width; x += tileSize {
+			tiles = append(tiles, tile{
+				minX: x,
+				minY: y,
+				maxX: min(x+tileSize, width),
+				maxY: min(y+tileSize, height),
+			})
+		}
+	}
+	return tiles
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}